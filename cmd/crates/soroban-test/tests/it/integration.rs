@@ -3,6 +3,7 @@ mod constructor;
 mod cookbook;
 mod custom_types;
 mod dotenv;
+mod events;
 mod hello_world;
 mod keys;
 mod snapshot;
@@ -0,0 +1,40 @@
+use std::time::Duration;
+
+use soroban_rpc::GetLatestLedgerResponse;
+use soroban_test::TestEnv;
+
+use super::{hello_world::invoke_log, util::deploy_hello};
+
+#[tokio::test]
+async fn watch() {
+    let sandbox = &TestEnv::new();
+    let id = deploy_hello(sandbox).await;
+    let GetLatestLedgerResponse { sequence, .. } =
+        sandbox.client().get_latest_ledger().await.unwrap();
+    let mut cmd = sandbox.new_assert_cmd("events");
+    cmd.arg("--watch")
+        .arg("--poll-interval")
+        .arg("1")
+        .arg("--output")
+        .arg("json")
+        .arg("--start-ledger")
+        .arg(sequence.to_string())
+        .arg("--id")
+        .arg(&id)
+        .timeout(Duration::from_secs(20));
+    let watch = tokio::task::spawn_blocking(move || cmd.output().unwrap());
+
+    invoke_log(sandbox, &id);
+    // While the second invocation is submitted, the watcher polls and finds
+    // nothing new in the ledger of the first event.
+    invoke_log(sandbox, &id);
+
+    let output = watch.await.unwrap();
+    let stdout = String::from_utf8(output.stdout).unwrap();
+    let ids = serde_json::Deserializer::from_str(&stdout)
+        .into_iter::<serde_json::Value>()
+        .map(|event| event.unwrap()["id"].as_str().unwrap().to_string())
+        .collect::<Vec<_>>();
+    assert_eq!(ids.len(), 2, "{stdout}");
+    assert_ne!(ids[0], ids[1], "{stdout}");
+}
@@ -390,7 +390,7 @@ async fn invoke_prng_u64_in_range_test(sandbox: &TestEnv, id: &str) {
         .await
         .is_ok());
 }
-fn invoke_log(sandbox: &TestEnv, id: &str) {
+pub(crate) fn invoke_log(sandbox: &TestEnv, id: &str) {
     sandbox
         .new_assert_cmd("contract")
         .arg("invoke")
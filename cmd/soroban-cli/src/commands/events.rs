@@ -1,11 +1,12 @@
 use clap::{arg, command, Parser};
-use std::io;
+use std::{io, time::Duration};
 
 use crate::xdr::{self, Limits, ReadXdr};
 
 use super::{global, NetworkRunnable};
 use crate::{
     config::{self, locator, network},
+    print::Print,
     rpc,
 };
 
@@ -28,8 +29,26 @@ pub struct Cmd {
     #[arg(long, value_enum, default_value = "pretty")]
     output: OutputFormat,
     /// The maximum number of events to display (defer to the server-defined limit).
+    /// With `--watch`, this is the maximum number of events fetched per poll
+    /// and there is no limit on the total number displayed.
     #[arg(short, long, default_value = "10")]
     count: usize,
+    /// Keep polling for new events after the initial batch, printing them as
+    /// they arrive. Polling resumes from the last event seen, so no events are
+    /// missed if a request to the RPC server fails.
+    #[arg(long)]
+    watch: bool,
+    /// Seconds to wait between polls when using `--watch`.
+    #[arg(
+        long,
+        default_value = "5",
+        requires = "watch",
+        value_parser = clap::value_parser!(u64).range(1..)
+    )]
+    poll_interval: u64,
+    /// ID of the last event printed by `--watch`.
+    #[arg(skip)]
+    last_seen: Option<String>,
     /// A set of (up to 5) contract IDs to filter events on. This parameter can
     /// be passed multiple times, e.g. `--id C123.. --id C456..`, or passed with
     /// multiple parameters, e.g. `--id C123 C456`.
@@ -134,7 +153,7 @@ pub enum OutputFormat {
 }
 
 impl Cmd {
-    pub async fn run(&mut self) -> Result<(), Error> {
+    pub async fn run(&mut self, global_args: &global::Args) -> Result<(), Error> {
         // Validate that topics are made up of segments.
         for topic in &self.topic_filters {
             for (i, segment) in topic.split(',').enumerate() {
@@ -156,9 +175,86 @@ impl Cmd {
             }
         }
 
-        let response = self.run_against_rpc_server(None, None).await?;
+        if !self.watch {
+            let response = self.run_against_rpc_server(None, None).await?;
+            return self.print_events(&response.events);
+        }
+
+        let print = Print::new(global_args.quiet);
+        let (client, contract_ids) = self.client_and_contract_ids(None).await?;
+        self.last_seen.clone_from(&self.cursor);
+        // The first request is not retried, so that invalid arguments (e.g. a
+        // start ledger outside of the retention window) fail like they would
+        // without `--watch`.
+        let mut response = self.get_events(&client, &contract_ids).await?;
+        loop {
+            let unseen: Vec<_> = response
+                .events
+                .iter()
+                .filter(|event| self.is_unseen(&event.id))
+                .collect();
+            self.print_events(unseen)?;
+            self.advance(
+                response.events.last().map(|event| event.id.as_str()),
+                response.latest_ledger,
+            );
 
-        for event in &response.events {
+            // A full page means more events are likely waiting, so only wait
+            // once caught up.
+            let mut wait = response.events.len() < self.count;
+            loop {
+                if wait {
+                    tokio::time::sleep(Duration::from_secs(self.poll_interval)).await;
+                }
+                match self.get_events(&client, &contract_ids).await {
+                    Ok(r) => {
+                        response = r;
+                        break;
+                    }
+                    Err(Error::Rpc(e)) if is_transient(&e) => {
+                        print.warnln(format!("Failed to fetch events, retrying: {e}"));
+                        wait = true;
+                    }
+                    Err(e) => return Err(e),
+                }
+            }
+        }
+    }
+
+    /// Moves the `--watch` start position past a poll whose last event (if
+    /// any) had ID `last_id`.
+    fn advance(&mut self, last_id: Option<&str>, latest_ledger: u32) {
+        if let Some(id) = last_id {
+            self.start_ledger = None;
+            self.cursor = Some(id.to_string());
+            if self.is_unseen(id) {
+                self.last_seen = Some(id.to_string());
+            }
+            return;
+        }
+        let behind = !matches!(self.start_ledger, Some(l) if l >= latest_ledger);
+        let seen_ledger = self.last_seen.as_deref().and_then(event_ledger);
+        if behind && seen_ledger.map_or(true, |l| l < latest_ledger) {
+            // Nothing matched up to the latest ledger, so move forward to it;
+            // otherwise the start could fall out of the retention window while
+            // the filtered contracts are quiet. The cursor is kept while the
+            // latest ledger is still that of the last event seen, as starting
+            // from that ledger would return the event again.
+            self.start_ledger = Some(latest_ledger);
+            self.cursor = None;
+        }
+    }
+
+    fn is_unseen(&self, id: &str) -> bool {
+        // Event IDs are zero-padded, so they order lexicographically.
+        !matches!(self.last_seen.as_deref(), Some(seen) if id <= seen)
+    }
+
+    fn print_events<'a>(
+        &self,
+        events: impl IntoIterator<Item = &'a rpc::Event>,
+    ) -> Result<(), Error> {
+        for event in events {
             match self.output {
                 // Should we pretty-print the JSON like we're doing here or just
                 // dump an event in raw JSON on each line? The latter is easier
@@ -181,28 +277,10 @@ impl Cmd {
         Ok(())
     }
 
-    fn start(&self) -> Result<rpc::EventStart, Error> {
-        let start = match (self.start_ledger, self.cursor.clone()) {
-            (Some(start), _) => rpc::EventStart::Ledger(start),
-            (_, Some(c)) => rpc::EventStart::Cursor(c),
-            // should never happen because of required_unless_present flags
-            _ => return Err(Error::MissingStartLedgerAndCursor),
-        };
-        Ok(start)
-    }
-}
-
-#[async_trait::async_trait]
-impl NetworkRunnable for Cmd {
-    type Error = Error;
-    type Result = rpc::GetEventsResponse;
-
-    async fn run_against_rpc_server(
+    async fn client_and_contract_ids(
         &self,
-        _args: Option<&global::Args>,
         config: Option<&config::Args>,
-    ) -> Result<rpc::GetEventsResponse, Error> {
-        let start = self.start()?;
+    ) -> Result<(rpc::Client, Vec<String>), Error> {
         let network = if let Some(config) = config {
             Ok(config.get_network()?)
         } else {
@@ -224,15 +302,128 @@ impl NetworkRunnable for Cmd {
             })
             .collect::<Result<Vec<_>, Error>>()?;
 
-        Ok(client
+        Ok((client, contract_ids))
+    }
+
+    async fn get_events(
+        &self,
+        client: &rpc::Client,
+        contract_ids: &[String],
+    ) -> Result<rpc::GetEventsResponse, Error> {
+        client
             .get_events(
-                start,
+                self.start()?,
                 Some(self.event_type),
-                &contract_ids,
+                contract_ids,
                 &self.topic_filters,
                 Some(self.count),
             )
             .await
-            .map_err(Error::Rpc)?)
+            .map_err(Error::Rpc)
+    }
+
+    fn start(&self) -> Result<rpc::EventStart, Error> {
+        let start = match (self.start_ledger, self.cursor.clone()) {
+            (Some(start), _) => rpc::EventStart::Ledger(start),
+            (_, Some(c)) => rpc::EventStart::Cursor(c),
+            // should never happen because of required_unless_present flags
+            _ => return Err(Error::MissingStartLedgerAndCursor),
+        };
+        Ok(start)
+    }
+}
+
+#[async_trait::async_trait]
+impl NetworkRunnable for Cmd {
+    type Error = Error;
+    type Result = rpc::GetEventsResponse;
+
+    async fn run_against_rpc_server(
+        &self,
+        _args: Option<&global::Args>,
+        config: Option<&config::Args>,
+    ) -> Result<rpc::GetEventsResponse, Error> {
+        let (client, contract_ids) = self.client_and_contract_ids(config).await?;
+        self.get_events(&client, &contract_ids).await
+    }
+}
+
+/// The ledger of an event ID, whose first part is a TOID with the ledger
+/// sequence in its upper 32 bits.
+fn event_ledger(id: &str) -> Option<u32> {
+    let toid: u64 = id.split('-').next()?.parse().ok()?;
+    u32::try_from(toid >> 32).ok()
+}
+
+/// Whether an RPC error is a connection problem worth retrying, as opposed to
+/// the server rejecting the request.
+fn is_transient(e: &rpc::Error) -> bool {
+    matches!(
+        e,
+        rpc::Error::JsonRpc(
+            jsonrpsee_core::Error::Transport(_)
+                | jsonrpsee_core::Error::RequestTimeout
+                | jsonrpsee_core::Error::RestartNeeded(_)
+        )
+    )
+}
+
+#[cfg(test)]
+mod tests {
+    use super::*;
+
+    #[test]
+    fn test_poll_interval_requires_watch() {
+        let res = Cmd::try_parse_from(["events", "--start-ledger", "1", "--poll-interval", "1"]);
+        assert_eq!(
+            res.unwrap_err().kind(),
+            clap::error::ErrorKind::MissingRequiredArgument
+        );
+        let res = Cmd::try_parse_from([
+            "events",
+            "--start-ledger",
+            "1",
+            "--watch",
+            "--poll-interval",
+            "1",
+        ]);
+        assert!(res.is_ok());
+    }
+
+    #[test]
+    fn test_poll_interval_must_be_positive() {
+        let res = Cmd::try_parse_from([
+            "events",
+            "--start-ledger",
+            "1",
+            "--watch",
+            "--poll-interval",
+            "0",
+        ]);
+        assert_eq!(
+            res.unwrap_err().kind(),
+            clap::error::ErrorKind::ValueValidation
+        );
+    }
+
+    #[test]
+    fn test_watch_does_not_reprint_after_empty_poll() {
+        let mut cmd = Cmd::try_parse_from(["events", "--start-ledger", "40", "--watch"]).unwrap();
+        // An event in ledger 40.
+        let id = "0000000171798695937-0000000001";
+        assert_eq!(event_ledger(id), Some(40));
+        cmd.advance(Some(id), 40);
+        assert!(!cmd.is_unseen(id));
+        assert!(cmd.is_unseen("0000000171798695937-0000000002"));
+
+        // An empty poll while the event's ledger is still the latest one keeps
+        // the cursor, since starting from ledger 40 would return the event again.
+        cmd.advance(None, 40);
+        assert!(matches!(cmd.start().unwrap(), rpc::EventStart::Cursor(c) if c == id));
+
+        // Once a later ledger has closed, the start moves forward to it.
+        cmd.advance(None, 41);
+        assert!(matches!(cmd.start().unwrap(), rpc::EventStart::Ledger(41)));
+        assert!(!cmd.is_unseen(id));
     }
 }
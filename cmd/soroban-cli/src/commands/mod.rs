@@ -111,7 +111,7 @@ impl Root {
         match &mut self.cmd {
             Cmd::Completion(completion) => completion.run(),
             Cmd::Contract(contract) => contract.run(&self.global_args).await?,
-            Cmd::Events(events) => events.run().await?,
+            Cmd::Events(events) => events.run(&self.global_args).await?,
             Cmd::Xdr(xdr) => xdr.run()?,
             Cmd::Network(network) => network.run(&self.global_args).await?,
             Cmd::Container(container) => container.run(&self.global_args).await?,